@@ -0,0 +1,17 @@
+package core
+
+import (
+	"gopkg.in/src-d/go-queue.v1"
+
+	// Backends register themselves against the go-queue.v1 registry on
+	// import; the scheme of the URL passed to NewBroker picks one of them.
+	_ "gopkg.in/src-d/go-queue.v1/amqp"
+	_ "gopkg.in/src-d/go-queue.v1/nats"
+)
+
+// NewBroker returns the queue.Broker for the given URL, selecting the
+// backend (amqp://, nats://, ...) from the URL's scheme instead of hard
+// wiring a single transport.
+func NewBroker(url string) (queue.Broker, error) {
+	return queue.New(url)
+}
@@ -0,0 +1,176 @@
+// Package cgit scrapes cgit-powered repository indexes for clone URLs.
+package cgit
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/src-d/rovers/providers/forge"
+)
+
+func init() {
+	forge.Register(`cgit\.`, func(seedURL string) forge.ForgeScraper {
+		return newScraper(seedURL)
+	})
+}
+
+// Repository is the type returned by scraper.Next, kept as an alias so
+// existing callers that imported cgit.Repository keep compiling after the
+// ForgeScraper extraction.
+type Repository = forge.Repository
+
+// scraper implements forge.ForgeScraper for cgit's HTML layout: a listing
+// page with one row per repository, and per-repository pages advertising
+// their clone URLs.
+type scraper struct {
+	baseURL   string
+	nextPage  string
+	repoURLs  []string
+	pos       int
+	listed    bool
+	preferred []string
+}
+
+func newScraper(baseURL string) *scraper {
+	return &scraper{
+		baseURL:   baseURL,
+		nextPage:  baseURL,
+		preferred: forge.DefaultProtocolPreference,
+	}
+}
+
+// Next returns the next repository found under the scraper's seed URL. It
+// returns io.EOF once every listing page has been visited and every
+// repository on them has been inspected; the call after that starts over
+// from the seed URL.
+func (s *scraper) Next() (*Repository, error) {
+	for {
+		if s.pos < len(s.repoURLs) {
+			repoURL := s.repoURLs[s.pos]
+			s.pos++
+
+			repo, err := s.repoPage(repoURL)
+			if err != nil || repo == nil {
+				continue
+			}
+
+			return repo, nil
+		}
+
+		if s.listed && s.nextPage == "" {
+			s.nextPage = s.baseURL
+			s.listed = false
+			s.repoURLs = nil
+			s.pos = 0
+			return nil, io.EOF
+		}
+
+		if err := s.listPage(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// listPage fetches and parses the next listing page, filling repoURLs with
+// the repositories it links to and nextPage with the following page, if
+// any.
+func (s *scraper) listPage() error {
+	doc, err := s.getDocument(s.nextPage)
+	if err != nil {
+		return err
+	}
+	s.listed = true
+
+	if doc.Find("div#cgit").Length() == 0 && doc.Find("table.list").Length() == 0 {
+		return fmt.Errorf("cgit: %s doesn't look like a cgit page", s.nextPage)
+	}
+
+	s.pos = 0
+	s.repoURLs = nil
+	doc.Find("table.list tr td.sublevel-repo a, table.list tr td.toplevel a").Each(func(_ int, sel *goquery.Selection) {
+		href, ok := sel.Attr("href")
+		if !ok {
+			return
+		}
+
+		s.repoURLs = append(s.repoURLs, s.resolve(href))
+	})
+
+	s.nextPage = ""
+	doc.Find("div.content ul.pager a").Each(func(_ int, sel *goquery.Selection) {
+		if strings.Contains(strings.ToLower(sel.Text()), "next") {
+			if href, ok := sel.Attr("href"); ok {
+				s.nextPage = s.resolve(href)
+			}
+		}
+	})
+
+	return nil
+}
+
+// repoPage fetches a repository's page and picks its preferred clone URL.
+// It returns a nil Repository, rather than an error, when the page has no
+// usable clone URL, so that Next can keep looking at the remaining
+// repositories instead of aborting the whole scrape.
+func (s *scraper) repoPage(repoURL string) (*Repository, error) {
+	doc, err := s.getDocument(repoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var urls []string
+	doc.Find("table.list tr td.url, a.url").Each(func(_ int, sel *goquery.Selection) {
+		if href, ok := sel.Attr("href"); ok {
+			urls = append(urls, href)
+		} else {
+			urls = append(urls, strings.TrimSpace(sel.Text()))
+		}
+	})
+
+	main := s.mainUrl(urls)
+	if main == "" {
+		return nil, nil
+	}
+
+	html, _ := doc.Html()
+	return &Repository{Html: html, RepositoryURL: main}, nil
+}
+
+// mainUrl picks the clone URL rovers should use out of the ones a
+// repository advertises, using the scraper's protocol preference order.
+func (s *scraper) mainUrl(urls []string) string {
+	return forge.SelectMainURL(urls, s.preferred)
+}
+
+func (s *scraper) getDocument(rawURL string) (*goquery.Document, error) {
+	resp, err := http.Get(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cgit: unexpected status %d for %s", resp.StatusCode, rawURL)
+	}
+
+	return goquery.NewDocumentFromReader(resp.Body)
+}
+
+func (s *scraper) resolve(href string) string {
+	base, err := url.Parse(s.baseURL)
+	if err != nil {
+		return href
+	}
+
+	ref, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+
+	return base.ResolveReference(ref).String()
+}
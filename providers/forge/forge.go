@@ -0,0 +1,76 @@
+// Package forge provides the common abstraction shared by every scraper
+// that crawls a git forge's web UI for repositories to clone: cgit, Gitea,
+// Gogs, Gitweb, and a generic fallback for pages that just list clone URLs.
+package forge
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Repository is a single repository found by a ForgeScraper.
+type Repository struct {
+	// Html is the raw HTML of the repository's own page, for scrapers that
+	// fetch one (cgit); it's empty for scrapers that don't.
+	Html string
+	// RepositoryURL is the URL rovers should use to clone the repository.
+	RepositoryURL string
+}
+
+// ForgeScraper is implemented by every forge-specific scraper. Next returns
+// the next repository found, io.EOF once the seed has been fully crawled,
+// and any other error if the seed couldn't be scraped at all.
+type ForgeScraper interface {
+	Next() (*Repository, error)
+}
+
+// Factory creates a ForgeScraper for the given seed URL.
+type Factory func(seedURL string) ForgeScraper
+
+type registration struct {
+	pattern *regexp.Regexp
+	factory Factory
+}
+
+var registry []registration
+
+// fallback is the Factory New uses when no registered pattern matches a
+// seed's host, set by RegisterFallback. It's kept separate from registry so
+// that New only tries it after every registered pattern, whatever order the
+// adapters' init functions ran in.
+var fallback Factory
+
+// Register associates a hostname pattern with a Factory, so that New can
+// pick the right ForgeScraper for a seed URL without per-host code at the
+// call site. hostnamePattern is compiled as a regexp and matched against
+// the seed URL's host.
+func Register(hostnamePattern string, factory Factory) {
+	registry = append(registry, registration{
+		pattern: regexp.MustCompile(hostnamePattern),
+		factory: factory,
+	})
+}
+
+// RegisterFallback sets the Factory New falls back to when no pattern
+// registered through Register matches a seed's host. Registering a new
+// fallback replaces any previous one.
+func RegisterFallback(factory Factory) {
+	fallback = factory
+}
+
+// New returns the ForgeScraper registered for host, falling back to the
+// Factory set with RegisterFallback if no pattern matches it, or an error
+// if neither does.
+func New(host, seedURL string) (ForgeScraper, error) {
+	for _, r := range registry {
+		if r.pattern.MatchString(host) {
+			return r.factory(seedURL), nil
+		}
+	}
+
+	if fallback != nil {
+		return fallback(seedURL), nil
+	}
+
+	return nil, fmt.Errorf("forge: no scraper registered for host %q", host)
+}
@@ -0,0 +1,46 @@
+package forge
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+type ForgeSuite struct{}
+
+var _ = Suite(&ForgeSuite{})
+
+type stubScraper struct{ seedURL string }
+
+func (s *stubScraper) Next() (*Repository, error) { return nil, nil }
+
+func (s *ForgeSuite) TestNew_MatchesRegisteredPattern(c *C) {
+	defer resetRegistry()
+
+	Register(`stub\.`, func(seedURL string) ForgeScraper { return &stubScraper{seedURL: seedURL} })
+
+	scraper, err := New("stub.example.com", "https://stub.example.com/")
+	c.Assert(err, IsNil)
+	c.Assert(scraper.(*stubScraper).seedURL, Equals, "https://stub.example.com/")
+}
+
+func (s *ForgeSuite) TestNew_FallsBackWhenNothingMatches(c *C) {
+	defer resetRegistry()
+
+	RegisterFallback(func(seedURL string) ForgeScraper { return &stubScraper{seedURL: seedURL} })
+
+	scraper, err := New("unknown.example.com", "https://unknown.example.com/")
+	c.Assert(err, IsNil)
+	c.Assert(scraper.(*stubScraper).seedURL, Equals, "https://unknown.example.com/")
+}
+
+func (s *ForgeSuite) TestNew_NoMatchNoFallback(c *C) {
+	defer resetRegistry()
+
+	scraper, err := New("unknown.example.com", "https://unknown.example.com/")
+	c.Assert(err, NotNil)
+	c.Assert(scraper, IsNil)
+}
+
+func resetRegistry() {
+	registry = nil
+	fallback = nil
+}
@@ -0,0 +1,32 @@
+package forge
+
+import "strings"
+
+// DefaultProtocolPreference is the protocol order used by SelectMainURL when
+// none is given: prefer HTTPS clones, then the git:// protocol, then plain
+// HTTP, and ssh:// last since it usually requires keys the crawler doesn't
+// have.
+var DefaultProtocolPreference = []string{"https", "git", "http", "ssh"}
+
+// SelectMainURL returns the URL from urls whose scheme comes first in
+// preference. URLs whose scheme isn't in preference at all are ignored. It
+// returns "" if urls is empty or none of its schemes are in preference.
+func SelectMainURL(urls []string, preference []string) string {
+	for _, scheme := range preference {
+		for _, u := range urls {
+			if urlScheme(u) == scheme {
+				return u
+			}
+		}
+	}
+
+	return ""
+}
+
+func urlScheme(u string) string {
+	if i := strings.Index(u, "://"); i >= 0 {
+		return u[:i]
+	}
+
+	return ""
+}
@@ -0,0 +1,44 @@
+package forge
+
+import (
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type URLSuite struct{}
+
+var _ = Suite(&URLSuite{})
+
+func (s *URLSuite) TestSelectMainURL(c *C) {
+	const (
+		sshUrl   = "ssh://host/repo.git"
+		gitUrl   = "git://host/repo.git"
+		httpUrl  = "http://host/repo.git"
+		httpsUrl = "https://host/repo.git"
+		otherUrl = "other://host/repo.git"
+		noResult = ""
+	)
+
+	cases := []struct {
+		in  []string
+		out string
+	}{
+		{in: []string{sshUrl, gitUrl, httpUrl, httpsUrl}, out: httpsUrl},
+		{in: []string{otherUrl}, out: noResult},
+		{in: []string{httpUrl}, out: httpUrl},
+		{in: []string{gitUrl, httpUrl}, out: gitUrl},
+		{in: nil, out: noResult},
+	}
+
+	for _, d := range cases {
+		c.Assert(SelectMainURL(d.in, DefaultProtocolPreference), Equals, d.out)
+	}
+}
+
+func (s *URLSuite) TestSelectMainURL_CustomPreference(c *C) {
+	urls := []string{"http://host/repo.git", "ssh://host/repo.git"}
+	c.Assert(SelectMainURL(urls, []string{"ssh", "http"}), Equals, "ssh://host/repo.git")
+}
@@ -0,0 +1,103 @@
+// Package generic scrapes a page for anything that looks like a git clone
+// URL, for forges that don't fit cgit, Gitea/Gogs or Gitweb's layouts.
+package generic
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/src-d/rovers/providers/forge"
+)
+
+func init() {
+	forge.RegisterFallback(New)
+}
+
+// cloneURLPattern matches the clone URL schemes rovers knows how to use,
+// wherever they show up on the page: an href, or plain text such as a
+// "git clone <url>" snippet.
+var cloneURLPattern = regexp.MustCompile(`\b(?:https?|git|ssh)://[^\s"'<>]+\.git\b`)
+
+// scraper implements forge.ForgeScraper by scanning a single page for any
+// text or href that looks like a clone URL. It's the fallback registered
+// for hosts that don't match a more specific adapter.
+type scraper struct {
+	pageURL string
+	urls    []string
+	pos     int
+	fetched bool
+}
+
+func newScraper(pageURL string) *scraper {
+	return &scraper{pageURL: pageURL}
+}
+
+// New returns a forge.ForgeScraper for pageURL. Unlike the other adapters in
+// providers/, this one isn't registered against a hostname pattern: init
+// registers it as forge.New's fallback instead, for seeds that don't match
+// cgit, Gitea/Gogs or Gitweb.
+func New(pageURL string) forge.ForgeScraper {
+	return newScraper(pageURL)
+}
+
+// Next returns the next clone URL found on the page, and io.EOF once
+// they've all been returned.
+func (s *scraper) Next() (*forge.Repository, error) {
+	if !s.fetched {
+		if err := s.fetch(); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.pos >= len(s.urls) {
+		return nil, io.EOF
+	}
+
+	u := s.urls[s.pos]
+	s.pos++
+
+	return &forge.Repository{RepositoryURL: u}, nil
+}
+
+func (s *scraper) fetch() error {
+	s.fetched = true
+
+	resp, err := http.Get(s.pageURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("generic: unexpected status %d for %s", resp.StatusCode, s.pageURL)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	seen := map[string]bool{}
+	add := func(u string) {
+		if cloneURLPattern.MatchString(u) && !seen[u] {
+			seen[u] = true
+			s.urls = append(s.urls, u)
+		}
+	}
+
+	doc.Find("a[href]").Each(func(_ int, sel *goquery.Selection) {
+		if href, ok := sel.Attr("href"); ok {
+			add(href)
+		}
+	})
+
+	for _, m := range cloneURLPattern.FindAllString(doc.Text(), -1) {
+		add(m)
+	}
+
+	return nil
+}
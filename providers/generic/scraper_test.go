@@ -0,0 +1,75 @@
+package generic
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/src-d/rovers/providers/forge"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type GenericScraperSuite struct{}
+
+var _ = Suite(&GenericScraperSuite{})
+
+func (s *GenericScraperSuite) SetUpTest(c *C) {
+	httpmock.Activate()
+}
+
+func (s *GenericScraperSuite) TearDownTest(c *C) {
+	httpmock.DeactivateAndReset()
+}
+
+const pageHTML = `
+<html><body>
+<a href="https://forge.example.com/foo.git">foo</a>
+<p>git clone git://forge.example.com/bar.git</p>
+<a href="https://forge.example.com/about">about</a>
+</body></html>`
+
+func (s *GenericScraperSuite) TestNext(c *C) {
+	httpmock.RegisterResponder(http.MethodGet, "https://forge.example.com/projects",
+		httpmock.NewStringResponder(http.StatusOK, pageHTML))
+
+	scraper := New("https://forge.example.com/projects")
+
+	u, err := scraper.Next()
+	c.Assert(err, IsNil)
+	c.Assert(u.RepositoryURL, Equals, "https://forge.example.com/foo.git")
+
+	u, err = scraper.Next()
+	c.Assert(err, IsNil)
+	c.Assert(u.RepositoryURL, Equals, "git://forge.example.com/bar.git")
+
+	u, err = scraper.Next()
+	c.Assert(err, Equals, io.EOF)
+	c.Assert(u, IsNil)
+}
+
+func (s *GenericScraperSuite) TestRegisteredAsForgeFallback(c *C) {
+	httpmock.RegisterResponder(http.MethodGet, "https://unknown.example.com/projects",
+		httpmock.NewStringResponder(http.StatusOK, pageHTML))
+
+	scraper, err := forge.New("unknown.example.com", "https://unknown.example.com/projects")
+	c.Assert(err, IsNil)
+
+	u, err := scraper.Next()
+	c.Assert(err, IsNil)
+	c.Assert(u.RepositoryURL, Equals, "https://forge.example.com/foo.git")
+}
+
+func (s *GenericScraperSuite) TestNext_UnexpectedStatus(c *C) {
+	httpmock.RegisterResponder(http.MethodGet, "https://forge.example.com/projects",
+		httpmock.NewStringResponder(http.StatusNotFound, ""))
+
+	scraper := New("https://forge.example.com/projects")
+
+	u, err := scraper.Next()
+	c.Assert(err, NotNil)
+	c.Assert(u, IsNil)
+}
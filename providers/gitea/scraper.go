@@ -0,0 +1,106 @@
+// Package gitea scrapes the paginated JSON repository index exposed by
+// Gitea and Gogs instances at /explore/repos.
+package gitea
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/src-d/rovers/providers/forge"
+)
+
+func init() {
+	forge.Register(`gitea\.|gogs\.`, func(seedURL string) forge.ForgeScraper {
+		return newScraper(seedURL)
+	})
+}
+
+type repoListResponse struct {
+	OK   bool `json:"ok"`
+	Data []struct {
+		FullName string `json:"full_name"`
+		CloneURL string `json:"clone_url"`
+		SSHURL   string `json:"ssh_url"`
+	} `json:"data"`
+}
+
+// scraper implements forge.ForgeScraper for Gitea/Gogs's /explore/repos
+// paginated JSON API.
+type scraper struct {
+	baseURL   string
+	page      int
+	pending   []forge.Repository
+	pos       int
+	exhausted bool
+	preferred []string
+}
+
+func newScraper(baseURL string) *scraper {
+	return &scraper{baseURL: baseURL, page: 1, preferred: forge.DefaultProtocolPreference}
+}
+
+// Next returns the next repository advertised by the instance's explore
+// API, fetching subsequent pages as needed, and io.EOF once a page comes
+// back empty.
+func (s *scraper) Next() (*forge.Repository, error) {
+	for {
+		if s.pos < len(s.pending) {
+			r := s.pending[s.pos]
+			s.pos++
+			return &r, nil
+		}
+
+		if s.exhausted {
+			return nil, io.EOF
+		}
+
+		if err := s.fetchPage(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (s *scraper) fetchPage() error {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/explore/repos?page=%d", s.baseURL, s.page), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("gitea: unexpected status %d for page %d", resp.StatusCode, s.page)
+	}
+
+	var body repoListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return err
+	}
+
+	if len(body.Data) == 0 {
+		s.exhausted = true
+		return nil
+	}
+
+	s.pending = s.pending[:0]
+	s.pos = 0
+	for _, repo := range body.Data {
+		main := forge.SelectMainURL([]string{repo.CloneURL, repo.SSHURL}, s.preferred)
+		if main == "" {
+			continue
+		}
+
+		s.pending = append(s.pending, forge.Repository{RepositoryURL: main})
+	}
+
+	s.page++
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package gitea
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type GiteaScraperSuite struct{}
+
+var _ = Suite(&GiteaScraperSuite{})
+
+func (s *GiteaScraperSuite) SetUpTest(c *C) {
+	httpmock.Activate()
+}
+
+func (s *GiteaScraperSuite) TearDownTest(c *C) {
+	httpmock.DeactivateAndReset()
+}
+
+func (s *GiteaScraperSuite) TestNext_Paginates(c *C) {
+	httpmock.RegisterResponder(http.MethodGet, "https://gitea.example.com/explore/repos?page=1",
+		httpmock.NewStringResponder(http.StatusOK, `{
+			"ok": true,
+			"data": [
+				{"full_name": "foo/bar", "clone_url": "https://gitea.example.com/foo/bar.git", "ssh_url": "ssh://gitea.example.com/foo/bar.git"},
+				{"full_name": "foo/baz", "clone_url": "", "ssh_url": "ssh://gitea.example.com/foo/baz.git"}
+			]
+		}`))
+	httpmock.RegisterResponder(http.MethodGet, "https://gitea.example.com/explore/repos?page=2",
+		httpmock.NewStringResponder(http.StatusOK, `{"ok": true, "data": []}`))
+
+	scraper := newScraper("https://gitea.example.com")
+
+	u, err := scraper.Next()
+	c.Assert(err, IsNil)
+	c.Assert(u.RepositoryURL, Equals, "https://gitea.example.com/foo/bar.git")
+
+	u, err = scraper.Next()
+	c.Assert(err, IsNil)
+	c.Assert(u.RepositoryURL, Equals, "ssh://gitea.example.com/foo/baz.git")
+
+	u, err = scraper.Next()
+	c.Assert(err, Equals, io.EOF)
+	c.Assert(u, IsNil)
+}
+
+func (s *GiteaScraperSuite) TestNext_UnexpectedStatus(c *C) {
+	httpmock.RegisterResponder(http.MethodGet, "https://gitea.example.com/explore/repos?page=1",
+		httpmock.NewStringResponder(http.StatusInternalServerError, ""))
+
+	scraper := newScraper("https://gitea.example.com")
+
+	u, err := scraper.Next()
+	c.Assert(err, NotNil)
+	c.Assert(u, IsNil)
+}
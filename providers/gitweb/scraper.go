@@ -0,0 +1,118 @@
+// Package gitweb scrapes Gitweb's project list, served at ?a=project_list.
+package gitweb
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/src-d/rovers/providers/forge"
+)
+
+func init() {
+	forge.Register(`gitweb\.|/gitweb`, func(seedURL string) forge.ForgeScraper {
+		return newScraper(seedURL)
+	})
+}
+
+// scraper implements forge.ForgeScraper for Gitweb's project_list action.
+// Gitweb doesn't paginate project_list, so a single fetch is enough.
+type scraper struct {
+	baseURL   string
+	projects  []string
+	pos       int
+	fetched   bool
+	preferred []string
+}
+
+func newScraper(baseURL string) *scraper {
+	return &scraper{baseURL: baseURL, preferred: forge.DefaultProtocolPreference}
+}
+
+// Next returns the next project listed by ?a=project_list, and io.EOF once
+// every project has been returned.
+func (s *scraper) Next() (*forge.Repository, error) {
+	if !s.fetched {
+		if err := s.fetchProjectList(); err != nil {
+			return nil, err
+		}
+	}
+
+	for s.pos < len(s.projects) {
+		project := s.projects[s.pos]
+		s.pos++
+
+		main := forge.SelectMainURL(s.cloneURLs(project), s.preferred)
+		if main == "" {
+			continue
+		}
+
+		return &forge.Repository{RepositoryURL: main}, nil
+	}
+
+	return nil, io.EOF
+}
+
+func (s *scraper) fetchProjectList() error {
+	s.fetched = true
+
+	listURL := s.baseURL
+	if u, err := url.Parse(s.baseURL); err == nil && u.Path == "" {
+		listURL += "/"
+	}
+
+	resp, err := http.Get(listURL + "?a=project_list")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("gitweb: unexpected status %d for %s", resp.StatusCode, s.baseURL)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	doc.Find("table.project_list tr td.list a").Each(func(_ int, sel *goquery.Selection) {
+		href, ok := sel.Attr("href")
+		if !ok {
+			return
+		}
+
+		if p := projectFromHref(href); p != "" {
+			s.projects = append(s.projects, p)
+		}
+	})
+
+	return nil
+}
+
+// cloneURLs builds the URL Gitweb's smart HTTP backend serves a project
+// under.
+func (s *scraper) cloneURLs(project string) []string {
+	return []string{
+		strings.TrimSuffix(s.baseURL, "/") + "/" + project,
+	}
+}
+
+func projectFromHref(href string) string {
+	const marker = "p="
+	i := strings.Index(href, marker)
+	if i < 0 {
+		return ""
+	}
+
+	rest := href[i+len(marker):]
+	if end := strings.IndexAny(rest, ";&"); end >= 0 {
+		rest = rest[:end]
+	}
+
+	return rest
+}
@@ -0,0 +1,91 @@
+package gitweb
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type GitwebScraperSuite struct{}
+
+var _ = Suite(&GitwebScraperSuite{})
+
+func (s *GitwebScraperSuite) SetUpTest(c *C) {
+	httpmock.Activate()
+}
+
+func (s *GitwebScraperSuite) TearDownTest(c *C) {
+	httpmock.DeactivateAndReset()
+}
+
+const projectListHTML = `
+<html><body>
+<table class="project_list">
+<tr><td class="list"><a href="?p=foo.git;a=summary">foo.git</a></td></tr>
+<tr><td class="list"><a href="?p=bar.git;a=summary">bar.git</a></td></tr>
+</table>
+</body></html>`
+
+func (s *GitwebScraperSuite) TestNext(c *C) {
+	httpmock.RegisterResponder(http.MethodGet, "http://gitweb.example.com/?a=project_list",
+		httpmock.NewStringResponder(http.StatusOK, projectListHTML))
+
+	scraper := newScraper("http://gitweb.example.com")
+
+	u, err := scraper.Next()
+	c.Assert(err, IsNil)
+	c.Assert(u.RepositoryURL, Equals, "http://gitweb.example.com/foo.git")
+
+	u, err = scraper.Next()
+	c.Assert(err, IsNil)
+	c.Assert(u.RepositoryURL, Equals, "http://gitweb.example.com/bar.git")
+
+	u, err = scraper.Next()
+	c.Assert(err, Equals, io.EOF)
+	c.Assert(u, IsNil)
+}
+
+func (s *GitwebScraperSuite) TestNext_SkipsUnusableProjects(c *C) {
+	httpmock.RegisterResponder(http.MethodGet, "http://gitweb.example.com/?a=project_list",
+		httpmock.NewStringResponder(http.StatusOK, projectListHTML))
+
+	scraper := newScraper("http://gitweb.example.com")
+	// Narrow the preference so none of cloneURLs' http(s) candidates match,
+	// forcing every project on the page to be skipped.
+	scraper.preferred = []string{"ssh"}
+
+	u, err := scraper.Next()
+	c.Assert(err, Equals, io.EOF)
+	c.Assert(u, IsNil)
+}
+
+func (s *GitwebScraperSuite) TestNext_UnexpectedStatus(c *C) {
+	httpmock.RegisterResponder(http.MethodGet, "http://gitweb.example.com/?a=project_list",
+		httpmock.NewStringResponder(http.StatusInternalServerError, ""))
+
+	scraper := newScraper("http://gitweb.example.com")
+
+	u, err := scraper.Next()
+	c.Assert(err, NotNil)
+	c.Assert(u, IsNil)
+}
+
+func (s *GitwebScraperSuite) TestProjectFromHref(c *C) {
+	cases := []struct {
+		in  string
+		out string
+	}{
+		{in: "?p=foo.git;a=summary", out: "foo.git"},
+		{in: "?p=bar.git&a=summary", out: "bar.git"},
+		{in: "?a=summary", out: ""},
+	}
+
+	for _, d := range cases {
+		c.Assert(projectFromHref(d.in), Equals, d.out)
+	}
+}
@@ -1,6 +1,7 @@
 package queue
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -10,13 +11,19 @@ import (
 
 	"gopkg.in/src-d/go-queue.v1"
 
+	amqp "github.com/rabbitmq/amqp091-go"
+
 	"github.com/jpillora/backoff"
 	"github.com/kelseyhightower/envconfig"
-	"github.com/streadway/amqp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"gopkg.in/src-d/go-errors.v1"
 	"gopkg.in/src-d/go-log.v0"
 )
 
+const tracerName = "gopkg.in/src-d/go-queue.v1/amqp"
+
 func init() {
 	err := envconfig.Process("amqp", &DefaultConfiguration)
 	if err != nil {
@@ -45,6 +52,10 @@ type Configuration struct {
 	BackoffMin    time.Duration `envconfig:"BACKOFF_MIN" default:"200ms"`
 	BackoffMax    time.Duration `envconfig:"BACKOFF_MAX" default:"30s"`
 	BackoffFactor float64       `envconfig:"BACKOFF_FACTOR" default:"2"`
+
+	// ConfirmTimeout bounds how long a reliable Broker (see NewReliable)
+	// waits for the server to confirm a publishing before giving up.
+	ConfirmTimeout time.Duration `envconfig:"CONFIRM_TIMEOUT" default:"5s"`
 }
 
 var consumerSeq uint64
@@ -54,6 +65,8 @@ var (
 	ErrOpenChannel      = errors.NewKind("failed to open a channel: %s")
 	ErrRetrievingHeader = errors.NewKind("error retrieving '%s' header from message %s")
 	ErrRepublishingJobs = errors.NewKind("couldn't republish some jobs : %s")
+	ErrConfirmTimeout   = errors.NewKind("timed out waiting for a publisher confirm for message %s")
+	ErrUnroutable       = errors.NewKind("message %s was returned as unroutable: %s (%d)")
 )
 
 // Broker implements the queue.Broker interface for AMQP, such as RabbitMQ.
@@ -64,15 +77,135 @@ type Broker struct {
 	connErrors chan *amqp.Error
 	stop       chan struct{}
 	backoff    *backoff.Backoff
+
+	// reliable, when true, makes Publish/PublishDelayed block until the
+	// broker confirms the message and treats unroutable messages as errors.
+	reliable    bool
+	confirmMut  sync.Mutex
+	confirmSeq  uint64
+	confirmWait map[uint64]chan publishResult
+	returnWait  map[string]chan publishResult
+
+	collector Collector
+	tracer    trace.Tracer
+}
+
+// Option configures a Broker created by New or NewReliable.
+type Option func(*Broker)
+
+// Reliable enables publisher confirms and mandatory returns, equivalent to
+// using NewReliable instead of New.
+func Reliable() Option {
+	return func(b *Broker) { b.reliable = true }
+}
+
+// WithCollector makes the Broker report throughput, latency and connection
+// events to c instead of discarding them.
+func WithCollector(c Collector) Option {
+	return func(b *Broker) { b.collector = c }
+}
+
+// WithTracer makes the Broker emit OpenTelemetry spans for Publish and
+// Consume using t instead of the global tracer provider.
+func WithTracer(t trace.Tracer) Option {
+	return func(b *Broker) { b.tracer = t }
+}
+
+// publishResult carries the outcome of a reliable publish, whether it came
+// from a NotifyPublish confirmation or a NotifyReturn.
+type publishResult struct {
+	ack    bool
+	reason string
+	code   int
 }
 
 type connection interface {
 	connection() *amqp.Connection
 	channel() *amqp.Channel
+	publish(ctx context.Context, exchange, key string, msg amqp.Publishing) error
+	metrics() Collector
+	trace() trace.Tracer
+}
+
+func (b *Broker) metrics() Collector {
+	if b.collector == nil {
+		return noopCollector{}
+	}
+
+	return b.collector
+}
+
+func (b *Broker) trace() trace.Tracer {
+	if b.tracer == nil {
+		return otel.Tracer(tracerName)
+	}
+
+	return b.tracer
+}
+
+// publish sends msg over b's channel. When the broker is reliable it also
+// enables the mandatory flag, blocks until the broker confirms the message
+// and turns an unroutable message into ErrUnroutable.
+func (b *Broker) publish(ctx context.Context, exchange, key string, msg amqp.Publishing) error {
+	if !b.reliable {
+		return b.channel().PublishWithContext(ctx, exchange, key, false, false, msg)
+	}
+
+	b.confirmMut.Lock()
+	tag := b.confirmSeq + 1
+	wait := make(chan publishResult, 2)
+	b.confirmWait[tag] = wait
+	b.returnWait[msg.MessageId] = wait
+	err := b.channel().PublishWithContext(ctx, exchange, key, true, false, msg)
+	if err == nil {
+		b.confirmSeq = tag
+	} else {
+		delete(b.confirmWait, tag)
+		delete(b.returnWait, msg.MessageId)
+	}
+	b.confirmMut.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	select {
+	case r := <-wait:
+		if !r.ack {
+			return ErrUnroutable.New(msg.MessageId, r.reason, r.code)
+		}
+		return nil
+	case <-time.After(DefaultConfiguration.ConfirmTimeout):
+		b.confirmMut.Lock()
+		delete(b.confirmWait, tag)
+		delete(b.returnWait, msg.MessageId)
+		b.confirmMut.Unlock()
+		return ErrConfirmTimeout.New(msg.MessageId)
+	case <-ctx.Done():
+		b.confirmMut.Lock()
+		delete(b.confirmWait, tag)
+		delete(b.returnWait, msg.MessageId)
+		b.confirmMut.Unlock()
+		return ctx.Err()
+	}
 }
 
 // New creates a new AMQPBroker.
-func New(url string) (queue.Broker, error) {
+func New(url string, opts ...Option) (queue.Broker, error) {
+	return newBroker(url, opts...)
+}
+
+// NewReliable creates a new AMQPBroker with publisher confirms enabled: the
+// underlying channel is put into confirm mode and Publish/PublishDelayed
+// block until the broker acks or nacks the message, or ConfirmTimeout
+// elapses. Messages that the broker cannot route are surfaced as
+// ErrUnroutable instead of being silently dropped. It's equivalent to
+// calling New with the Reliable option.
+func NewReliable(url string, opts ...Option) (queue.Broker, error) {
+	return newBroker(url, append(opts, Reliable())...)
+}
+
+func newBroker(url string, opts ...Option) (queue.Broker, error) {
 	conn, err := amqp.Dial(url)
 	if err != nil {
 		return nil, ErrConnectionFailed.New(err)
@@ -93,6 +226,20 @@ func New(url string) (queue.Broker, error) {
 			Factor: DefaultConfiguration.BackoffFactor,
 			Jitter: false,
 		},
+		confirmWait: make(map[uint64]chan publishResult),
+		returnWait:  make(map[string]chan publishResult),
+		collector:   noopCollector{},
+		tracer:      otel.Tracer(tracerName),
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	if b.reliable {
+		if err := b.enableConfirms(ch); err != nil {
+			return nil, err
+		}
 	}
 
 	go b.manageConnection(url)
@@ -100,6 +247,46 @@ func New(url string) (queue.Broker, error) {
 	return b, nil
 }
 
+// enableConfirms puts ch into confirm mode and wires up the goroutines that
+// resolve pending Publish calls from NotifyPublish/NotifyReturn.
+func (b *Broker) enableConfirms(ch *amqp.Channel) error {
+	if err := ch.Confirm(false); err != nil {
+		return err
+	}
+
+	confirms := ch.NotifyPublish(make(chan amqp.Confirmation, 64))
+	returns := ch.NotifyReturn(make(chan amqp.Return, 64))
+
+	go b.handleConfirms(confirms)
+	go b.handleReturns(returns)
+
+	return nil
+}
+
+func (b *Broker) handleConfirms(confirms <-chan amqp.Confirmation) {
+	for c := range confirms {
+		b.confirmMut.Lock()
+		if wait, ok := b.confirmWait[c.DeliveryTag]; ok {
+			delete(b.confirmWait, c.DeliveryTag)
+			wait <- publishResult{ack: c.Ack}
+		}
+		b.confirmMut.Unlock()
+	}
+}
+
+func (b *Broker) handleReturns(returns <-chan amqp.Return) {
+	for r := range returns {
+		log.Errorf(ErrUnroutable.New(r.MessageId, r.ReplyText, r.ReplyCode), "unroutable message returned")
+
+		b.confirmMut.Lock()
+		if wait, ok := b.returnWait[r.MessageId]; ok {
+			delete(b.returnWait, r.MessageId)
+			wait <- publishResult{ack: false, reason: r.ReplyText, code: int(r.ReplyCode)}
+		}
+		b.confirmMut.Unlock()
+	}
+}
+
 func (b *Broker) manageConnection(url string) {
 	b.connErrors = make(chan *amqp.Error)
 	b.conn.NotifyClose(b.connErrors)
@@ -108,9 +295,12 @@ func (b *Broker) manageConnection(url string) {
 		select {
 		case err := <-b.connErrors:
 			log.Errorf(err, "amqp connection error")
+			b.collector.ConnectionError()
 			b.mut.Lock()
 			if err != nil {
+				start := time.Now()
 				b.conn, b.ch = b.reconnect(url)
+				b.collector.Reconnected(time.Since(start))
 				b.connErrors = make(chan *amqp.Error)
 				b.conn.NotifyClose(b.connErrors)
 			}
@@ -147,6 +337,25 @@ func (b *Broker) tryChannel(conn *amqp.Connection) *amqp.Channel {
 	for {
 		ch, err := conn.Channel()
 		if err == nil {
+			if b.reliable {
+				if err := b.enableConfirms(ch); err != nil {
+					log.Errorf(err, "error enabling publisher confirms, new retry")
+					time.Sleep(b.backoff.Duration())
+					continue
+				}
+
+				b.confirmMut.Lock()
+				b.confirmSeq = 0
+				// The new channel's delivery tags restart at 1 too, so any
+				// entry left over from the old (now-dead) channel must be
+				// dropped: otherwise a confirm for an unrelated message
+				// published on the new channel could land on a stale tag
+				// and resolve the wrong, old publish() call.
+				b.confirmWait = make(map[uint64]chan publishResult)
+				b.returnWait = make(map[string]chan publishResult)
+				b.confirmMut.Unlock()
+			}
+
 			b.backoff.Reset()
 			return ch
 		}
@@ -170,6 +379,16 @@ func (b *Broker) channel() *amqp.Channel {
 }
 
 func (b *Broker) newBuriedQueue(mainQueueName string) (q amqp.Queue, rex string, err error) {
+	_, span := b.trace().Start(context.Background(), "queue.declare_buried",
+		trace.WithAttributes(attribute.String("messaging.destination", mainQueueName)))
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+		}
+		span.End()
+		b.metrics().BuriedQueueDeclared(mainQueueName, err)
+	}()
+
 	ch, err := b.conn.Channel()
 	if err != nil {
 		return
@@ -202,24 +421,85 @@ func (b *Broker) newBuriedQueue(mainQueueName string) (q amqp.Queue, rex string,
 	return
 }
 
-// Queue returns the queue with the given name.
+// QueueOptions controls how QueueWith declares a queue and the exchange it
+// binds its dead letters to, plus the defaults applied to messages published
+// through it. The zero value is not meant to be used directly; start from
+// DefaultQueueOptions and override what's needed.
+type QueueOptions struct {
+	// Durable, AutoDelete and Exclusive are passed as-is to QueueDeclare.
+	Durable    bool
+	AutoDelete bool
+	Exclusive  bool
+
+	// MaxPriority sets x-max-priority. A value of 0 disables priority
+	// queueing for this queue.
+	MaxPriority uint8
+
+	// DeadLetterExchange and DeadLetterRoutingKey override the topology
+	// used for rejected/expired messages. When either is empty, the queue
+	// falls back to the buried exchange/queue that QueueWith provisions
+	// for this queue name.
+	DeadLetterExchange   string
+	DeadLetterRoutingKey string
+
+	// DefaultContentType and DefaultDeliveryMode are used by Publish and
+	// PublishDelayed whenever the Job doesn't set its own ContentType.
+	DefaultContentType  string
+	DefaultDeliveryMode uint8
+}
+
+// DefaultQueueOptions returns the options historically used by Queue:
+// durable, non-exclusive, not auto-deleted, urgent-priority capable and
+// persistent messages, dead-lettering into a per-queue buried exchange.
+func DefaultQueueOptions() QueueOptions {
+	return QueueOptions{
+		Durable:             true,
+		MaxPriority:         uint8(queue.PriorityUrgent),
+		DefaultDeliveryMode: uint8(amqp.Persistent),
+	}
+}
+
+// Queue returns the queue with the given name, declared with
+// DefaultQueueOptions. It's a thin wrapper around QueueWith kept for
+// backward compatibility.
 func (b *Broker) Queue(name string) (queue.Queue, error) {
+	return b.QueueWith(name, DefaultQueueOptions())
+}
+
+// QueueWith returns the queue with the given name, declared using opts. A
+// buried queue/exchange pair is always provisioned for the name, and is
+// used as the dead-letter target unless opts overrides it.
+func (b *Broker) QueueWith(name string, opts QueueOptions) (queue.Queue, error) {
 	buriedQueue, rex, err := b.newBuriedQueue(name)
 	if err != nil {
 		return nil, err
 	}
 
+	dlx := opts.DeadLetterExchange
+	if dlx == "" {
+		dlx = rex
+	}
+
+	dlrk := opts.DeadLetterRoutingKey
+	if dlrk == "" {
+		dlrk = name
+	}
+
+	args := amqp.Table{
+		"x-dead-letter-exchange":    dlx,
+		"x-dead-letter-routing-key": dlrk,
+	}
+	if opts.MaxPriority > 0 {
+		args["x-max-priority"] = opts.MaxPriority
+	}
+
 	q, err := b.ch.QueueDeclare(
-		name,  // name
-		true,  // durable
-		false, // delete when unused
-		false, // exclusive
+		name,
+		opts.Durable,
+		opts.AutoDelete,
+		opts.Exclusive,
 		false, // no-wait
-		amqp.Table{
-			"x-dead-letter-exchange":    rex,
-			"x-dead-letter-routing-key": name,
-			"x-max-priority":            uint8(queue.PriorityUrgent),
-		},
+		args,
 	)
 
 	if err != nil {
@@ -227,9 +507,11 @@ func (b *Broker) Queue(name string) (queue.Queue, error) {
 	}
 
 	return &Queue{
-		conn:        b,
-		queue:       q,
-		buriedQueue: &Queue{conn: b, queue: buriedQueue},
+		conn:                b,
+		queue:               q,
+		buriedQueue:         &Queue{conn: b, queue: buriedQueue},
+		defaultContentType:  opts.DefaultContentType,
+		defaultDeliveryMode: opts.DefaultDeliveryMode,
 	}, nil
 }
 
@@ -249,10 +531,35 @@ type Queue struct {
 	conn        connection
 	queue       amqp.Queue
 	buriedQueue *Queue
+
+	defaultContentType  string
+	defaultDeliveryMode uint8
+}
+
+func (q *Queue) contentType(j *queue.Job) string {
+	if j.ContentType != "" {
+		return j.ContentType
+	}
+
+	return q.defaultContentType
+}
+
+func (q *Queue) deliveryMode() uint8 {
+	if q.defaultDeliveryMode != 0 {
+		return q.defaultDeliveryMode
+	}
+
+	return amqp.Persistent
 }
 
 // Publish publishes the given Job to the Queue.
 func (q *Queue) Publish(j *queue.Job) error {
+	return q.PublishWithContext(context.Background(), j)
+}
+
+// PublishWithContext publishes the given Job to the Queue, aborting if the
+// given context is done before the broker accepts the publishing.
+func (q *Queue) PublishWithContext(ctx context.Context, j *queue.Job) error {
 	if j == nil || j.Size() == 0 {
 		return queue.ErrEmptyJob.New()
 	}
@@ -266,26 +573,51 @@ func (q *Queue) Publish(j *queue.Job) error {
 		headers[DefaultConfiguration.ErrorHeader] = j.ErrorType
 	}
 
-	return q.conn.channel().Publish(
-		"",           // exchange
-		q.queue.Name, // routing key
-		false,        // mandatory
-		false,
-		amqp.Publishing{
-			DeliveryMode: amqp.Persistent,
-			MessageId:    j.ID,
-			Priority:     uint8(j.Priority),
-			Timestamp:    j.Timestamp,
-			ContentType:  j.ContentType,
-			Body:         j.Raw,
-			Headers:      headers,
-		},
+	return q.instrumentedPublish(ctx, q.queue.Name, j, amqp.Publishing{
+		DeliveryMode: q.deliveryMode(),
+		MessageId:    j.ID,
+		Priority:     uint8(j.Priority),
+		Timestamp:    j.Timestamp,
+		ContentType:  q.contentType(j),
+		Body:         j.Raw,
+		Headers:      headers,
+	})
+}
+
+// instrumentedPublish wraps conn.publish with the OpenTelemetry span and
+// Collector reporting shared by Publish and PublishDelayed.
+func (q *Queue) instrumentedPublish(ctx context.Context, routingKey string, j *queue.Job, msg amqp.Publishing) error {
+	ctx, span := q.conn.trace().Start(ctx, "queue.publish",
+		trace.WithAttributes(
+			attribute.String("messaging.destination", q.queue.Name),
+			attribute.String("messaging.message_id", j.ID),
+			attribute.Int64("messaging.rovers.priority", int64(j.Priority)),
+			attribute.Int64("messaging.rovers.retries", int64(j.Retries)),
+		),
 	)
+	defer span.End()
+
+	start := time.Now()
+	err := q.conn.publish(ctx, "", routingKey, msg)
+	q.conn.metrics().PublishedJob(q.queue.Name, uint8(j.Priority), time.Since(start), err)
+
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return err
 }
 
 // PublishDelayed publishes the given Job with a given delay. Delayed messages
 // wont go into the buried queue if they fail.
 func (q *Queue) PublishDelayed(j *queue.Job, delay time.Duration) error {
+	return q.PublishDelayedWithContext(context.Background(), j, delay)
+}
+
+// PublishDelayedWithContext publishes the given Job with a given delay,
+// aborting if the given context is done before the broker accepts the
+// publishing. Delayed messages wont go into the buried queue if they fail.
+func (q *Queue) PublishDelayedWithContext(ctx context.Context, j *queue.Job, delay time.Duration) error {
 	if j == nil || j.Size() == 0 {
 		return queue.ErrEmptyJob.New()
 	}
@@ -309,20 +641,14 @@ func (q *Queue) PublishDelayed(j *queue.Job, delay time.Duration) error {
 		return err
 	}
 
-	return q.conn.channel().Publish(
-		"", // exchange
-		delayedQueue.Name,
-		false,
-		false,
-		amqp.Publishing{
-			DeliveryMode: amqp.Persistent,
-			MessageId:    j.ID,
-			Priority:     uint8(j.Priority),
-			Timestamp:    j.Timestamp,
-			ContentType:  j.ContentType,
-			Body:         j.Raw,
-		},
-	)
+	return q.instrumentedPublish(ctx, delayedQueue.Name, j, amqp.Publishing{
+		DeliveryMode: q.deliveryMode(),
+		MessageId:    j.ID,
+		Priority:     uint8(j.Priority),
+		Timestamp:    j.Timestamp,
+		ContentType:  q.contentType(j),
+		Body:         j.Raw,
+	})
 }
 
 type jobErr struct {
@@ -337,6 +663,10 @@ func (q *Queue) RepublishBuried(conditions ...queue.RepublishConditionFunc) erro
 		return fmt.Errorf("buriedQueue is nil, called RepublishBuried on the internal buried queue?")
 	}
 
+	if depth, err := q.conn.channel().QueueInspect(q.buriedQueue.queue.Name); err == nil {
+		q.conn.metrics().BuriedQueueDepth(q.queue.Name, depth.Messages)
+	}
+
 	// enforce prefetching only one job
 	iter, err := q.buriedQueue.Consume(1)
 	if err != nil {
@@ -446,6 +776,13 @@ func (q *Queue) Transaction(txcb queue.TxCallback) error {
 // Implements Queue.  The advertisedWindow value will be the exact
 // number of undelivered jobs in transit, not just the minium.
 func (q *Queue) Consume(advertisedWindow int) (queue.JobIter, error) {
+	return q.ConsumeWithContext(context.Background(), advertisedWindow)
+}
+
+// ConsumeWithContext behaves like Consume, but the returned JobIter's Next
+// unblocks and returns queue.ErrAlreadyClosed as soon as the given context
+// is cancelled, instead of blocking forever waiting for a delivery.
+func (q *Queue) ConsumeWithContext(ctx context.Context, advertisedWindow int) (queue.JobIter, error) {
 	ch, err := q.conn.connection().Channel()
 	if err != nil {
 		return nil, ErrOpenChannel.New(err)
@@ -458,7 +795,9 @@ func (q *Queue) Consume(advertisedWindow int) (queue.JobIter, error) {
 	}
 
 	id := q.consumeID()
-	c, err := ch.Consume(
+	ctx, cancel := context.WithCancel(ctx)
+	c, err := ch.ConsumeWithContext(
+		ctx,
 		q.queue.Name, // queue
 		id,           // consumer
 		false,        // autoAck
@@ -468,10 +807,20 @@ func (q *Queue) Consume(advertisedWindow int) (queue.JobIter, error) {
 		nil,          // args
 	)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
 
-	return &JobIter{id: id, ch: ch, c: c}, nil
+	return &JobIter{
+		id:        id,
+		ch:        ch,
+		c:         c,
+		ctx:       ctx,
+		cancel:    cancel,
+		queueName: q.queue.Name,
+		collector: q.conn.metrics(),
+		tracer:    q.conn.trace(),
+	}, nil
 }
 
 func (q *Queue) consumeID() string {
@@ -484,19 +833,51 @@ func (q *Queue) consumeID() string {
 
 // JobIter implements the JobIter interface for AMQP.
 type JobIter struct {
-	id string
-	ch *amqp.Channel
-	c  <-chan amqp.Delivery
+	id        string
+	ch        *amqp.Channel
+	c         <-chan amqp.Delivery
+	ctx       context.Context
+	cancel    context.CancelFunc
+	queueName string
+	collector Collector
+	tracer    trace.Tracer
 }
 
-// Next returns the next job in the iter.
+// Next returns the next job in the iter. It unblocks with
+// queue.ErrAlreadyClosed if the iter is closed or its context is cancelled
+// before a delivery arrives.
 func (i *JobIter) Next() (*queue.Job, error) {
-	d, ok := <-i.c
-	if !ok {
+	select {
+	case d, ok := <-i.c:
+		if !ok {
+			return nil, queue.ErrAlreadyClosed.New()
+		}
+
+		return i.deliver(&d)
+	case <-i.ctx.Done():
 		return nil, queue.ErrAlreadyClosed.New()
 	}
+}
+
+func (i *JobIter) deliver(d *amqp.Delivery) (*queue.Job, error) {
+	j, err := fromDelivery(d, i.queueName, i.collector)
+	if err != nil {
+		return nil, err
+	}
+
+	_, span := i.tracer.Start(context.Background(), "queue.consume",
+		trace.WithAttributes(
+			attribute.String("messaging.destination", i.queueName),
+			attribute.String("messaging.message_id", j.ID),
+			attribute.Int64("messaging.rovers.priority", int64(j.Priority)),
+			attribute.Int64("messaging.rovers.retries", int64(j.Retries)),
+		),
+	)
+	span.End()
 
-	return fromDelivery(&d)
+	i.collector.ConsumedJob(i.queueName, uint8(j.Priority), j.Retries)
+
+	return j, nil
 }
 
 func (i *JobIter) nextNonBlocking() (*queue.Job, error) {
@@ -506,14 +887,19 @@ func (i *JobIter) nextNonBlocking() (*queue.Job, error) {
 			return nil, queue.ErrAlreadyClosed.New()
 		}
 
-		return fromDelivery(&d)
+		return i.deliver(&d)
+	case <-i.ctx.Done():
+		return nil, queue.ErrAlreadyClosed.New()
 	default:
 		return nil, nil
 	}
 }
 
-// Close closes the channel of the JobIter.
+// Close cancels the consumer's context and closes the channel of the
+// JobIter, unblocking any goroutine waiting inside Next.
 func (i *JobIter) Close() error {
+	i.cancel()
+
 	if err := i.ch.Cancel(i.id, false); err != nil {
 		return err
 	}
@@ -523,22 +909,34 @@ func (i *JobIter) Close() error {
 
 // Acknowledger implements the Acknowledger for AMQP.
 type Acknowledger struct {
-	ack amqp.Acknowledger
-	id  uint64
+	ack       amqp.Acknowledger
+	id        uint64
+	queueName string
+	collector Collector
 }
 
 // Ack signals ackwoledgement.
 func (a *Acknowledger) Ack() error {
-	return a.ack.Ack(a.id, false)
+	err := a.ack.Ack(a.id, false)
+	if err == nil {
+		a.collector.AckedJob(a.queueName)
+	}
+
+	return err
 }
 
 // Reject signals rejection. If requeue is false, the job will go to the buried
 // queue until Queue.RepublishBuried() is called.
 func (a *Acknowledger) Reject(requeue bool) error {
-	return a.ack.Reject(a.id, requeue)
+	err := a.ack.Reject(a.id, requeue)
+	if err == nil {
+		a.collector.RejectedJob(a.queueName, requeue)
+	}
+
+	return err
 }
 
-func fromDelivery(d *amqp.Delivery) (*queue.Job, error) {
+func fromDelivery(d *amqp.Delivery, queueName string, collector Collector) (*queue.Job, error) {
 	j, err := queue.NewJob()
 	if err != nil {
 		return nil, err
@@ -548,7 +946,7 @@ func fromDelivery(d *amqp.Delivery) (*queue.Job, error) {
 	j.Priority = queue.Priority(d.Priority)
 	j.Timestamp = d.Timestamp
 	j.ContentType = d.ContentType
-	j.Acknowledger = &Acknowledger{d.Acknowledger, d.DeliveryTag}
+	j.Acknowledger = &Acknowledger{ack: d.Acknowledger, id: d.DeliveryTag, queueName: queueName, collector: collector}
 	j.Raw = d.Body
 
 	if retries, ok := d.Headers[DefaultConfiguration.RetriesHeader]; ok {
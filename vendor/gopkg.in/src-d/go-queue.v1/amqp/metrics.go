@@ -0,0 +1,43 @@
+package queue
+
+import "time"
+
+// Collector receives instrumentation events from the AMQP broker. Every
+// method must be safe for concurrent use, cheap and non-blocking: a
+// Collector is called synchronously from the broker's hot paths and must
+// never panic.
+type Collector interface {
+	// PublishedJob is called after every Publish/PublishDelayed attempt,
+	// successful or not.
+	PublishedJob(queueName string, priority uint8, latency time.Duration, err error)
+	// ConsumedJob is called every time JobIter.Next hands back a job.
+	ConsumedJob(queueName string, priority uint8, retries int32)
+	// AckedJob is called when a job is acknowledged.
+	AckedJob(queueName string)
+	// RejectedJob is called when a job is rejected.
+	RejectedJob(queueName string, requeue bool)
+	// BuriedQueueDepth reports the current number of messages sitting in a
+	// queue's buried queue, sampled at the start of RepublishBuried.
+	BuriedQueueDepth(queueName string, depth int)
+	// BuriedQueueDeclared is called every time a queue's buried queue and
+	// exchange are declared, successfully or not.
+	BuriedQueueDeclared(queueName string, err error)
+	// Reconnected is called every time manageConnection re-establishes a
+	// dropped connection, with the backoff duration that preceded it.
+	Reconnected(backoff time.Duration)
+	// ConnectionError is called every time the connection notifies a
+	// close/error event, before a reconnect is attempted.
+	ConnectionError()
+}
+
+// noopCollector is the default Collector used when none is configured.
+type noopCollector struct{}
+
+func (noopCollector) PublishedJob(string, uint8, time.Duration, error) {}
+func (noopCollector) ConsumedJob(string, uint8, int32)                 {}
+func (noopCollector) AckedJob(string)                                  {}
+func (noopCollector) RejectedJob(string, bool)                         {}
+func (noopCollector) BuriedQueueDepth(string, int)                     {}
+func (noopCollector) BuriedQueueDeclared(string, error)                {}
+func (noopCollector) Reconnected(time.Duration)                        {}
+func (noopCollector) ConnectionError()                                 {}
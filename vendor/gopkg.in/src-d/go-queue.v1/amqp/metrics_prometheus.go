@@ -0,0 +1,158 @@
+package queue
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PrometheusCollector is a Collector backed by Prometheus metrics. Register
+// it once with the default registry (or a custom one via NewPrometheusCollectorFor)
+// and pass it to New/NewReliable through WithCollector.
+type PrometheusCollector struct {
+	published        *prometheus.CounterVec
+	publishLatency   *prometheus.HistogramVec
+	consumed         *prometheus.CounterVec
+	acked            *prometheus.CounterVec
+	rejected         *prometheus.CounterVec
+	buriedQueueDepth *prometheus.GaugeVec
+	buriedQueueDecls *prometheus.CounterVec
+	reconnects       prometheus.Counter
+	reconnectBackoff prometheus.Histogram
+	connectionErrors prometheus.Counter
+}
+
+// NewPrometheusCollector creates and registers a PrometheusCollector against
+// the default Prometheus registry.
+func NewPrometheusCollector() *PrometheusCollector {
+	return NewPrometheusCollectorFor(prometheus.DefaultRegisterer)
+}
+
+// NewPrometheusCollectorFor creates and registers a PrometheusCollector
+// against reg.
+func NewPrometheusCollectorFor(reg prometheus.Registerer) *PrometheusCollector {
+	c := &PrometheusCollector{
+		published: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "rovers",
+			Subsystem: "amqp",
+			Name:      "published_jobs_total",
+			Help:      "Total number of jobs published, by queue and outcome.",
+		}, []string{"queue", "priority", "outcome"}),
+		publishLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "rovers",
+			Subsystem: "amqp",
+			Name:      "publish_latency_seconds",
+			Help:      "Latency of Publish/PublishDelayed calls.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"queue"}),
+		consumed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "rovers",
+			Subsystem: "amqp",
+			Name:      "consumed_jobs_total",
+			Help:      "Total number of jobs handed back by JobIter.Next, by queue and priority.",
+		}, []string{"queue", "priority"}),
+		acked: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "rovers",
+			Subsystem: "amqp",
+			Name:      "acked_jobs_total",
+			Help:      "Total number of jobs acknowledged, by queue.",
+		}, []string{"queue"}),
+		rejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "rovers",
+			Subsystem: "amqp",
+			Name:      "rejected_jobs_total",
+			Help:      "Total number of jobs rejected, by queue and whether they were requeued.",
+		}, []string{"queue", "requeue"}),
+		buriedQueueDepth: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "rovers",
+			Subsystem: "amqp",
+			Name:      "buried_queue_depth",
+			Help:      "Number of messages in a queue's buried queue, sampled at RepublishBuried time.",
+		}, []string{"queue"}),
+		buriedQueueDecls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "rovers",
+			Subsystem: "amqp",
+			Name:      "buried_queue_declarations_total",
+			Help:      "Total number of times a queue's buried queue/exchange were declared, by outcome.",
+		}, []string{"queue", "outcome"}),
+		reconnects: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "rovers",
+			Subsystem: "amqp",
+			Name:      "reconnects_total",
+			Help:      "Total number of times the broker reconnected to RabbitMQ.",
+		}),
+		reconnectBackoff: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "rovers",
+			Subsystem: "amqp",
+			Name:      "reconnect_backoff_seconds",
+			Help:      "Backoff duration observed before a successful reconnect.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		connectionErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "rovers",
+			Subsystem: "amqp",
+			Name:      "connection_errors_total",
+			Help:      "Total number of connection error notifications received.",
+		}),
+	}
+
+	reg.MustRegister(
+		c.published, c.publishLatency, c.consumed, c.acked, c.rejected,
+		c.buriedQueueDepth, c.buriedQueueDecls, c.reconnects, c.reconnectBackoff, c.connectionErrors,
+	)
+
+	return c
+}
+
+func (c *PrometheusCollector) PublishedJob(queueName string, priority uint8, latency time.Duration, err error) {
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+	}
+
+	c.published.WithLabelValues(queueName, priorityLabel(priority), outcome).Inc()
+	c.publishLatency.WithLabelValues(queueName).Observe(latency.Seconds())
+}
+
+func (c *PrometheusCollector) ConsumedJob(queueName string, priority uint8, retries int32) {
+	c.consumed.WithLabelValues(queueName, priorityLabel(priority)).Inc()
+}
+
+func (c *PrometheusCollector) AckedJob(queueName string) {
+	c.acked.WithLabelValues(queueName).Inc()
+}
+
+func (c *PrometheusCollector) RejectedJob(queueName string, requeue bool) {
+	c.rejected.WithLabelValues(queueName, requeueLabel(requeue)).Inc()
+}
+
+func (c *PrometheusCollector) BuriedQueueDepth(queueName string, depth int) {
+	c.buriedQueueDepth.WithLabelValues(queueName).Set(float64(depth))
+}
+
+func (c *PrometheusCollector) BuriedQueueDeclared(queueName string, err error) {
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+	}
+
+	c.buriedQueueDecls.WithLabelValues(queueName, outcome).Inc()
+}
+
+func (c *PrometheusCollector) Reconnected(backoff time.Duration) {
+	c.reconnects.Inc()
+	c.reconnectBackoff.Observe(backoff.Seconds())
+}
+
+func (c *PrometheusCollector) ConnectionError() {
+	c.connectionErrors.Inc()
+}
+
+func priorityLabel(p uint8) string {
+	return strconv.Itoa(int(p))
+}
+
+func requeueLabel(requeue bool) string {
+	return strconv.FormatBool(requeue)
+}
@@ -0,0 +1,490 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/src-d/go-queue.v1"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/nats-io/nats.go"
+	"gopkg.in/src-d/go-errors.v1"
+	"gopkg.in/src-d/go-log.v0"
+)
+
+func init() {
+	err := envconfig.Process("nats", &DefaultConfiguration)
+	if err != nil {
+		panic(err)
+	}
+
+	queue.Register("nats", func(uri string) (queue.Broker, error) {
+		return New(uri)
+	})
+}
+
+// DefaultConfiguration contains the default configuration initalized from
+// environment variables.
+var DefaultConfiguration Configuration
+
+// Configuration NATS configuration settings, this settings are set using the
+// envinroment varabiles.
+type Configuration struct {
+	// BuriedStreamSuffix must not contain '.', '*', '>', whitespace or path
+	// separators: it's appended to the queue name to build a JetStream
+	// stream/consumer name, and those characters aren't valid tokens in the
+	// $JS.API.STREAM.*/$JS.API.CONSUMER.* subjects NATS builds from it.
+	BuriedStreamSuffix       string `envconfig:"BURIED_STREAM_SUFFIX" default:"_buried"`
+	BuriedNonBlockingRetries int    `envconfig:"BURIED_BLOCKING_RETRIES" default:"3"`
+
+	RetriesHeader string `envconfig:"RETRIES_HEADER" default:"x-retries"`
+	ErrorHeader   string `envconfig:"ERROR_HEADER" default:"x-error-type"`
+
+	// AckWait is how long JetStream waits for an Ack before redelivering a
+	// message. It's also the margin PublishDelayed adds on top of the
+	// requested delay before its per-job stream expires and the fetch
+	// waiting on it gives up.
+	AckWait time.Duration `envconfig:"ACK_WAIT" default:"30s"`
+}
+
+var consumerSeq uint64
+
+var (
+	ErrConnectionFailed = errors.NewKind("failed to connect to NATS: %s")
+	ErrJetStream        = errors.NewKind("failed to get a JetStream context: %s")
+	ErrRetrievingHeader = errors.NewKind("error retrieving '%s' header from message %s")
+	ErrRepublishingJobs = errors.NewKind("couldn't republish some jobs : %s")
+)
+
+// Broker implements the queue.Broker interface for NATS JetStream.
+type Broker struct {
+	conn *nats.Conn
+	js   nats.JetStreamContext
+}
+
+// New creates a new Broker connected to the NATS server at the given URL,
+// with reconnection handled transparently by the underlying client.
+func New(url string) (queue.Broker, error) {
+	conn, err := nats.Connect(url,
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				log.Errorf(err, "nats connection error")
+			}
+		}),
+		nats.ReconnectHandler(func(_ *nats.Conn) {
+			log.Infof("nats connection restored")
+		}),
+	)
+	if err != nil {
+		return nil, ErrConnectionFailed.New(err)
+	}
+
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, ErrJetStream.New(err)
+	}
+
+	return &Broker{conn: conn, js: js}, nil
+}
+
+// Queue returns the queue with the given name, declaring its backing stream
+// and the stream used to hold buried jobs if they don't already exist.
+func (b *Broker) Queue(name string) (queue.Queue, error) {
+	if _, err := b.js.StreamInfo(name); err != nil {
+		if _, err := b.js.AddStream(&nats.StreamConfig{
+			Name:     name,
+			Subjects: []string{name},
+			Storage:  nats.FileStorage,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	buriedName := name + DefaultConfiguration.BuriedStreamSuffix
+	if _, err := b.js.StreamInfo(buriedName); err != nil {
+		if _, err := b.js.AddStream(&nats.StreamConfig{
+			Name:     buriedName,
+			Subjects: []string{buriedName},
+			Storage:  nats.FileStorage,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Queue{
+		conn:        b,
+		name:        name,
+		buriedQueue: &Queue{conn: b, name: buriedName},
+	}, nil
+}
+
+// Close closes the connection to NATS.
+func (b *Broker) Close() error {
+	b.conn.Close()
+	return nil
+}
+
+// Queue implements the Queue interface for NATS JetStream. Every Queue owns
+// a durable subject with the same name as the queue, mirroring the AMQP
+// broker's one-queue-one-routing-key model.
+type Queue struct {
+	conn        *Broker
+	name        string
+	buriedQueue *Queue
+}
+
+// Publish publishes the given Job to the Queue.
+func (q *Queue) Publish(j *queue.Job) error {
+	if j == nil || j.Size() == 0 {
+		return queue.ErrEmptyJob.New()
+	}
+
+	_, err := q.conn.js.PublishMsg(q.toMsg(j))
+	return err
+}
+
+// PublishDelayed publishes the given Job with a given delay. It works by
+// declaring a dedicated, short-lived stream for this job alone and
+// forwarding it into the main subject once the delay elapses, mirroring the
+// AMQP backend's per-TTL queue that dead-letters straight back to the main
+// queue. Keeping each delayed job on its own stream, rather than the shared
+// buried stream RepublishBuried drains, avoids both republishing a delayed
+// job early and two delayed jobs stepping on each other's consumer. Delayed
+// messages wont go into the buried queue if they fail.
+func (q *Queue) PublishDelayed(j *queue.Job, delay time.Duration) error {
+	if j == nil || j.Size() == 0 {
+		return queue.ErrEmptyJob.New()
+	}
+
+	delayedName := q.delayedStreamName(j.ID)
+	if _, err := q.conn.js.AddStream(&nats.StreamConfig{
+		Name:     delayedName,
+		Subjects: []string{delayedName},
+		Storage:  nats.FileStorage,
+		MaxAge:   delay + DefaultConfiguration.AckWait,
+	}); err != nil {
+		return err
+	}
+
+	msg := q.toMsg(j)
+	msg.Subject = delayedName
+	if _, err := q.conn.js.PublishMsg(msg); err != nil {
+		return err
+	}
+
+	go q.forwardAfterDelay(delayedName, j.ID, delay)
+
+	return nil
+}
+
+func (q *Queue) delayedStreamName(jobID string) string {
+	return q.name + DefaultConfiguration.BuriedStreamSuffix + "_delay_" + jobID
+}
+
+func (q *Queue) forwardAfterDelay(delayedName, jobID string, delay time.Duration) {
+	defer func() {
+		if err := q.conn.js.DeleteStream(delayedName); err != nil {
+			log.Errorf(err, "couldn't delete delayed stream for job %s", jobID)
+		}
+	}()
+
+	time.Sleep(delay)
+
+	// An unnamed (ephemeral) pull consumer is enough here: the stream holds
+	// only this one job and is torn down right after, so there's no durable
+	// consumer left behind to accumulate across jobs.
+	sub, err := q.conn.js.PullSubscribe(delayedName, "", nats.BindStream(delayedName), nats.ManualAck())
+	if err != nil {
+		log.Errorf(err, "couldn't create delayed subscription for job %s", jobID)
+		return
+	}
+	defer sub.Unsubscribe()
+
+	msgs, err := sub.Fetch(1, nats.MaxWait(DefaultConfiguration.AckWait))
+	if err != nil || len(msgs) == 0 {
+		return
+	}
+
+	j, err := fromMsg(msgs[0])
+	if err != nil {
+		log.Errorf(err, "couldn't rebuild job %s from delayed message", jobID)
+		return
+	}
+
+	if err := msgs[0].Ack(); err != nil {
+		log.Errorf(err, "couldn't ack delayed message %s", jobID)
+	}
+
+	if err := q.Publish(j); err != nil {
+		log.Errorf(err, "couldn't forward delayed job %s to %s", jobID, q.name)
+	}
+}
+
+func (q *Queue) toMsg(j *queue.Job) *nats.Msg {
+	msg := nats.NewMsg(q.name)
+	msg.Header.Set("Nats-Msg-Id", j.ID)
+	msg.Data = j.Raw
+
+	if j.Retries > 0 {
+		msg.Header.Set(DefaultConfiguration.RetriesHeader, fmt.Sprint(j.Retries))
+	}
+
+	if j.ErrorType != "" {
+		msg.Header.Set(DefaultConfiguration.ErrorHeader, j.ErrorType)
+	}
+
+	return msg
+}
+
+type jobErr struct {
+	job *queue.Job
+	err error
+}
+
+// RepublishBuried will republish in the main queue those jobs that timed out
+// without Ack or were Rejected with requeue = false and makes comply return
+// true.
+func (q *Queue) RepublishBuried(conditions ...queue.RepublishConditionFunc) error {
+	if q.buriedQueue == nil {
+		return fmt.Errorf("buriedQueue is nil, called RepublishBuried on the internal buried queue?")
+	}
+
+	// enforce prefetching only one job
+	iter, err := q.buriedQueue.Consume(1)
+	if err != nil {
+		return err
+	}
+
+	defer iter.Close()
+
+	retries := 0
+	var notComplying []*queue.Job
+	var errorsPublishing []*jobErr
+	for {
+		j, err := iter.(*JobIter).nextNonBlocking()
+		if err != nil {
+			return err
+		}
+
+		if j == nil {
+			if retries > DefaultConfiguration.BuriedNonBlockingRetries {
+				break
+			}
+
+			time.Sleep(50 * time.Millisecond)
+			retries++
+			continue
+		}
+
+		retries = 0
+
+		if err = j.Ack(); err != nil {
+			return err
+		}
+
+		if queue.RepublishConditions(conditions).Comply(j) {
+			if err = q.Publish(j); err != nil {
+				errorsPublishing = append(errorsPublishing, &jobErr{j, err})
+			}
+		} else {
+			notComplying = append(notComplying, j)
+		}
+	}
+
+	for _, job := range notComplying {
+		if err = job.Reject(true); err != nil {
+			return err
+		}
+	}
+
+	return q.handleRepublishErrors(errorsPublishing)
+}
+
+func (q *Queue) handleRepublishErrors(list []*jobErr) error {
+	if len(list) > 0 {
+		stringErrors := []string{}
+		for _, je := range list {
+			stringErrors = append(stringErrors, je.err.Error())
+			if err := q.buriedQueue.Publish(je.job); err != nil {
+				return err
+			}
+		}
+
+		return ErrRepublishingJobs.New(strings.Join(stringErrors, ": "))
+	}
+
+	return nil
+}
+
+// Transaction is not supported by the NATS backend: JetStream has no
+// equivalent to AMQP channel transactions, so the callback runs against the
+// Queue directly without any rollback guarantees.
+func (q *Queue) Transaction(txcb queue.TxCallback) error {
+	return txcb(q)
+}
+
+// Consume implements Queue. The advertisedWindow value is used as the
+// durable consumer's MaxAckPending, matching the AMQP broker's per-consumer
+// prefetch semantics.
+func (q *Queue) Consume(advertisedWindow int) (queue.JobIter, error) {
+	return q.ConsumeWithContext(context.Background(), advertisedWindow)
+}
+
+// ConsumeWithContext behaves like Consume, but the returned JobIter's Next
+// unblocks and returns queue.ErrAlreadyClosed as soon as the given context
+// is cancelled, instead of blocking forever waiting for a delivery.
+func (q *Queue) ConsumeWithContext(ctx context.Context, advertisedWindow int) (queue.JobIter, error) {
+	id := q.consumeID()
+
+	sub, err := q.conn.js.PullSubscribe(q.name, id,
+		nats.AckWait(DefaultConfiguration.AckWait),
+		nats.MaxAckPending(advertisedWindow),
+		nats.ManualAck(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	return &JobIter{js: q.conn.js, stream: q.name, durable: id, sub: sub, ctx: ctx, cancel: cancel}, nil
+}
+
+func (q *Queue) consumeID() string {
+	return fmt.Sprintf("%s-%s-%d",
+		filepath(os.Args[0]),
+		q.name,
+		atomic.AddUint64(&consumerSeq, 1),
+	)
+}
+
+func filepath(s string) string {
+	if i := strings.LastIndexByte(s, '/'); i >= 0 {
+		return s[i+1:]
+	}
+	return s
+}
+
+// JobIter implements the JobIter interface for NATS JetStream.
+type JobIter struct {
+	js      nats.JetStreamContext
+	stream  string
+	durable string
+	sub     *nats.Subscription
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+// Next returns the next job in the iter. It unblocks with
+// queue.ErrAlreadyClosed if the iter is closed or its context is cancelled
+// before a delivery arrives.
+func (i *JobIter) Next() (*queue.Job, error) {
+	for {
+		select {
+		case <-i.ctx.Done():
+			return nil, queue.ErrAlreadyClosed.New()
+		default:
+		}
+
+		msgs, err := i.sub.Fetch(1, nats.Context(i.ctx))
+		if err != nil {
+			if i.ctx.Err() != nil {
+				return nil, queue.ErrAlreadyClosed.New()
+			}
+			return nil, err
+		}
+
+		if len(msgs) == 0 {
+			continue
+		}
+
+		return fromMsg(msgs[0])
+	}
+}
+
+func (i *JobIter) nextNonBlocking() (*queue.Job, error) {
+	select {
+	case <-i.ctx.Done():
+		return nil, queue.ErrAlreadyClosed.New()
+	default:
+	}
+
+	msgs, err := i.sub.Fetch(1, nats.MaxWait(10*time.Millisecond))
+	if err != nil || len(msgs) == 0 {
+		return nil, nil
+	}
+
+	return fromMsg(msgs[0])
+}
+
+// Close cancels the consumer's context and unsubscribes, unblocking any
+// goroutine waiting inside Next. Unsubscribe alone leaves the durable
+// consumer registered server-side, so Close also explicitly deletes it to
+// avoid accumulating one consumer per Consume/RepublishBuried call.
+func (i *JobIter) Close() error {
+	i.cancel()
+
+	err := i.sub.Unsubscribe()
+
+	if derr := i.js.DeleteConsumer(i.stream, i.durable); derr != nil && err == nil {
+		err = derr
+	}
+
+	return err
+}
+
+// Acknowledger implements the Acknowledger for NATS JetStream.
+type Acknowledger struct {
+	msg *nats.Msg
+}
+
+// Ack signals acknowledgement.
+func (a *Acknowledger) Ack() error {
+	return a.msg.Ack()
+}
+
+// Reject signals rejection. If requeue is false, the message is terminated
+// and will only be seen again if it was published into a buried stream and
+// Queue.RepublishBuried() is called.
+func (a *Acknowledger) Reject(requeue bool) error {
+	if requeue {
+		return a.msg.Nak()
+	}
+
+	return a.msg.Term()
+}
+
+func fromMsg(m *nats.Msg) (*queue.Job, error) {
+	j, err := queue.NewJob()
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := m.Metadata()
+	if err != nil {
+		return nil, err
+	}
+
+	j.ID = m.Header.Get("Nats-Msg-Id")
+	j.Timestamp = meta.Timestamp
+	j.Acknowledger = &Acknowledger{msg: m}
+	j.Raw = m.Data
+
+	if retries := m.Header.Get(DefaultConfiguration.RetriesHeader); retries != "" {
+		if _, err := fmt.Sscanf(retries, "%d", &j.Retries); err != nil {
+			return nil, ErrRetrievingHeader.New(DefaultConfiguration.RetriesHeader, j.ID)
+		}
+	}
+
+	if errorType := m.Header.Get(DefaultConfiguration.ErrorHeader); errorType != "" {
+		j.ErrorType = errorType
+	}
+
+	return j, nil
+}
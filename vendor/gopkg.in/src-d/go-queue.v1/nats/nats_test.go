@@ -0,0 +1,169 @@
+package queue
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"gopkg.in/src-d/go-queue.v1"
+)
+
+// natsTestURL returns the NATS URL integration tests should dial, honouring
+// NATS_TEST_URI so CI can point at a throwaway JetStream server. It defaults
+// to the standard local dev port.
+func natsTestURL() string {
+	if u := os.Getenv("NATS_TEST_URI"); u != "" {
+		return u
+	}
+	return "nats://localhost:4222"
+}
+
+func newTestQueue(t *testing.T) (*Broker, *Queue) {
+	t.Helper()
+
+	broker, err := New(natsTestURL())
+	if err != nil {
+		t.Skipf("no NATS JetStream server available at %s: %s", natsTestURL(), err)
+	}
+	b := broker.(*Broker)
+
+	name := fmt.Sprintf("rovers-test-%d", time.Now().UnixNano())
+	q, err := b.Queue(name)
+	if err != nil {
+		b.Close()
+		t.Fatalf("Queue(%q): %s", name, err)
+	}
+
+	t.Cleanup(func() {
+		b.js.DeleteStream(name)
+		b.js.DeleteStream(name + DefaultConfiguration.BuriedStreamSuffix)
+		b.Close()
+	})
+
+	return b, q.(*Queue)
+}
+
+func testJob(t *testing.T, id string) *queue.Job {
+	t.Helper()
+
+	j, err := queue.NewJob()
+	if err != nil {
+		t.Fatalf("NewJob: %s", err)
+	}
+	j.ID = id
+	j.Raw = []byte("payload")
+
+	return j
+}
+
+// TestBroker_Queue_DeclaresValidStreamNames guards against the queue name
+// and buried stream suffix being concatenated into a stream/consumer name
+// that JetStream rejects (e.g. one containing '.').
+func TestBroker_Queue_DeclaresValidStreamNames(t *testing.T) {
+	newTestQueue(t)
+}
+
+func TestQueue_PublishConsume(t *testing.T) {
+	_, q := newTestQueue(t)
+
+	if err := q.Publish(testJob(t, "published")); err != nil {
+		t.Fatalf("Publish: %s", err)
+	}
+
+	iter, err := q.Consume(1)
+	if err != nil {
+		t.Fatalf("Consume: %s", err)
+	}
+	defer iter.Close()
+
+	j, err := iter.Next()
+	if err != nil {
+		t.Fatalf("Next: %s", err)
+	}
+	if j.ID != "published" {
+		t.Fatalf("Next: got job %q, want %q", j.ID, "published")
+	}
+	if err := j.Ack(); err != nil {
+		t.Fatalf("Ack: %s", err)
+	}
+}
+
+// TestQueue_PublishDelayed_DeliversAfterDelay exercises PublishDelayed
+// end-to-end: the job must not be visible on the main queue before the delay
+// elapses, and must be forwarded to it afterwards without ever touching the
+// buried stream RepublishBuried drains.
+func TestQueue_PublishDelayed_DeliversAfterDelay(t *testing.T) {
+	_, q := newTestQueue(t)
+
+	delay := 300 * time.Millisecond
+	if err := q.PublishDelayed(testJob(t, "delayed"), delay); err != nil {
+		t.Fatalf("PublishDelayed: %s", err)
+	}
+
+	iter, err := q.Consume(1)
+	if err != nil {
+		t.Fatalf("Consume: %s", err)
+	}
+	defer iter.Close()
+
+	ji := iter.(*JobIter)
+
+	if j, _ := ji.nextNonBlocking(); j != nil {
+		t.Fatalf("delayed job was visible on the main queue before its delay elapsed")
+	}
+
+	time.Sleep(delay + 200*time.Millisecond)
+
+	j, err := ji.nextNonBlocking()
+	if err != nil {
+		t.Fatalf("nextNonBlocking: %s", err)
+	}
+	if j == nil {
+		t.Fatalf("delayed job was never forwarded to the main queue")
+	}
+	if err := j.Ack(); err != nil {
+		t.Fatalf("Ack: %s", err)
+	}
+}
+
+// TestQueue_RepublishBuried_DoesNotRepublishOutstandingDelayedJob guards
+// against PublishDelayed and RepublishBuried sharing a stream: a delayed
+// job still waiting out its delay must not be republished early just
+// because RepublishBuried runs concurrently.
+func TestQueue_RepublishBuried_DoesNotRepublishOutstandingDelayedJob(t *testing.T) {
+	_, q := newTestQueue(t)
+
+	delay := 500 * time.Millisecond
+	if err := q.PublishDelayed(testJob(t, "delayed-vs-buried"), delay); err != nil {
+		t.Fatalf("PublishDelayed: %s", err)
+	}
+
+	if err := q.RepublishBuried(); err != nil {
+		t.Fatalf("RepublishBuried: %s", err)
+	}
+
+	iter, err := q.Consume(1)
+	if err != nil {
+		t.Fatalf("Consume: %s", err)
+	}
+	defer iter.Close()
+
+	ji := iter.(*JobIter)
+	if j, _ := ji.nextNonBlocking(); j != nil {
+		t.Fatalf("delayed job was republished early by a concurrent RepublishBuried")
+	}
+
+	time.Sleep(delay + 200*time.Millisecond)
+
+	j, err := ji.nextNonBlocking()
+	if err != nil {
+		t.Fatalf("nextNonBlocking: %s", err)
+	}
+	if j == nil {
+		t.Fatalf("delayed job was never forwarded to the main queue")
+	}
+	if err := j.Ack(); err != nil {
+		t.Fatalf("Ack: %s", err)
+	}
+}